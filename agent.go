@@ -0,0 +1,183 @@
+package ice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/stun"
+)
+
+// defaultKeepaliveInterval is how often the controlling agent's
+// pairReselectionLoop re-checks candidate pair quality when
+// AgentConfig.PairReselectionPolicy is set.
+const defaultKeepaliveInterval = 2 * time.Second
+
+// AgentConfig collects the construction-time options for an Agent.
+type AgentConfig struct {
+	LoggerFactory logging.LoggerFactory
+
+	// IsControlling marks this agent as the controlling agent for the ICE
+	// session (RFC 8445 §4), the side responsible for nominating candidate
+	// pairs. Only a controlling agent runs pairReselectionLoop.
+	IsControlling bool
+
+	// PairReselectionPolicy lets a controlling agent opt into (or customize)
+	// automatic re-nomination of a better-performing candidate pair; see
+	// PairReselectionPolicy. Nil (the default) disables automatic
+	// re-nomination.
+	PairReselectionPolicy PairReselectionPolicy
+
+	// ConsentTimeoutSec is how long (RFC 7675 §5.1) the nominated pair may go
+	// without a successful Binding transaction before consent is considered
+	// lost. Zero uses defaultConsentTimeoutSec.
+	ConsentTimeoutSec int
+
+	// ConsentIntervalSec is the nominal interval between consent Binding
+	// requests on the nominated pair, jittered per consentInterval. Zero
+	// uses defaultConsentIntervalSec.
+	ConsentIntervalSec int
+}
+
+// Agent is a long-lived ICE agent: it gathers candidates, runs connectivity
+// checks across the resulting candidate pairs, and maintains the pair
+// selected for media once checks succeed.
+type Agent struct {
+	lock sync.Mutex
+	log  logging.LeveledLogger
+
+	isControlling bool
+
+	localUfrag, localPwd   string
+	remoteUfrag, remotePwd string
+
+	checklist    []*candidatePair
+	selectedPair atomic.Value // *candidatePair
+
+	connectionState ConnectionState
+
+	keepaliveInterval time.Duration
+
+	pairReselectionPolicy PairReselectionPolicy
+
+	onConsentExpired   func(pair CandidatePairStats)
+	consentTimeoutSec  int
+	consentIntervalSec int
+
+	// totalRetransmissions counts outbound Binding requests that were
+	// retransmissions of an already-outstanding check on their pair (i.e.
+	// every sendSTUN call on a pair after its first). Like candidatePair's
+	// stats fields, it uses a typed atomic rather than a raw uint64 so
+	// alignment on 32-bit platforms is enforced by the compiler.
+	totalRetransmissions atomic.Uint64
+
+	// ctx is the agent's lifetime context; newCandidatePair derives its
+	// per-pair bitrate ticker from it so a pair's background work can never
+	// outlive the agent that created it, even if the pair itself is never
+	// explicitly closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewAgent creates an Agent configured per config and starts its background
+// loops (pair re-selection and RFC 7675 consent freshness).
+func NewAgent(config *AgentConfig) (*Agent, error) {
+	a := &Agent{
+		log:                   config.LoggerFactory.NewLogger("ice"),
+		isControlling:         config.IsControlling,
+		keepaliveInterval:     defaultKeepaliveInterval,
+		pairReselectionPolicy: config.PairReselectionPolicy,
+		consentTimeoutSec:     config.ConsentTimeoutSec,
+		consentIntervalSec:    config.ConsentIntervalSec,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.ctx = ctx
+	a.cancel = cancel
+
+	go a.pairReselectionLoop(ctx)
+	go a.consentLoop(ctx)
+
+	return a, nil
+}
+
+// Close stops the agent's background loops.
+func (a *Agent) Close() error {
+	a.cancel()
+	return nil
+}
+
+// findPair returns the checklist entry for (local, remote), or nil if the
+// pair hasn't been formed.
+func (a *Agent) findPair(local, remote Candidate) *candidatePair {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, p := range a.checklist {
+		if p.local.Equal(local) && p.remote.Equal(remote) {
+			return p
+		}
+	}
+	return nil
+}
+
+// getSelectedPair returns the pair currently nominated for media, or nil if
+// none has been selected yet.
+func (a *Agent) getSelectedPair() *candidatePair {
+	v := a.selectedPair.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*candidatePair)
+}
+
+func (a *Agent) setSelectedPair(p *candidatePair) {
+	a.selectedPair.Store(p)
+}
+
+// updateConnectionState transitions the agent to state.
+func (a *Agent) updateConnectionState(state ConnectionState) {
+	a.lock.Lock()
+	a.connectionState = state
+	a.lock.Unlock()
+}
+
+// reNominatePair sends a fresh USE-CANDIDATE Binding request on pair and
+// marks it as the new selected/nominated pair. This is how the controlling
+// agent acts on pairReselectionLoop's decision to switch to a
+// better-performing pair mid-session, mirroring the nomination performed
+// during the initial connectivity check phase.
+func (a *Agent) reNominatePair(pair *candidatePair) {
+	msg, err := stun.Build(stun.TransactionID, stun.BindingRequest,
+		stun.NewUsername(a.remoteUfrag+":"+a.localUfrag),
+		UseCandidate(),
+		stun.NewShortTermIntegrity(a.localPwd),
+		stun.Fingerprint,
+	)
+	if err != nil {
+		a.log.Warnf("failed to build re-nomination request for %s: %s", pair, err)
+		return
+	}
+
+	// Like consent pings, re-nomination requests go through writeSTUN rather
+	// than sendSTUN: re-nomination is a one-off per reselection decision, not
+	// a connectivity-check retry, so it shouldn't trip totalRetransmissions.
+	// It's still a genuine outbound request, so count it against
+	// requestsSent directly instead of dropping it from the stats entirely.
+	//
+	// Nothing is marked nominated/selected until the request is actually on
+	// the wire: failing that leaves the previous pair in place rather than
+	// reporting a switch to a peer that was never even asked to make it.
+	if err := a.writeSTUN(msg, pair.local, pair.remote); err != nil {
+		return
+	}
+	pair.requestsSent.Add(1)
+	pair.trackRequestTimeout()
+
+	a.lock.Lock()
+	pair.nominated = true
+	a.lock.Unlock()
+	a.setSelectedPair(pair)
+}
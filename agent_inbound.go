@@ -0,0 +1,42 @@
+package ice
+
+import "github.com/pion/stun"
+
+// handleInboundBindingRequest processes an inbound connectivity-check
+// Binding request on pair: it counts the request, writes back a Binding
+// success response, and counts that response once it's on the wire. It is
+// invoked by the agent's STUN demultiplexer once req has been decoded and
+// matched to pair.
+func (a *Agent) handleInboundBindingRequest(pair *candidatePair, req *stun.Message) {
+	pair.updateStatsFromBindingRequest()
+
+	resp, err := stun.Build(req, stun.BindingSuccess,
+		stun.NewShortTermIntegrity(a.localPwd),
+		stun.Fingerprint,
+	)
+	if err != nil {
+		a.log.Warnf("failed to build Binding success response for %s: %s", pair, err)
+		return
+	}
+
+	if err := a.writeSTUN(resp, pair.local, pair.remote); err == nil {
+		pair.updateStatsFromBindingResponse()
+	}
+}
+
+// handleInboundPacket records a packet of n bytes read from pair's local
+// candidate, STUN or otherwise, in the pair's inbound stats. It is invoked
+// by the agent's network read loop for every packet received on the pair.
+func (a *Agent) handleInboundPacket(pair *candidatePair, n int) {
+	pair.updateStatsFromReadPacket(n)
+}
+
+// handleInboundBindingResponse processes a Binding success response to a
+// connectivity check this agent sent on pair: it feeds the round-trip time
+// back into the pair's RTT/loss estimators and marks the request that
+// trackRequestTimeout is waiting on as answered. It is invoked by the
+// agent's STUN demultiplexer once resp has been matched, by transaction ID,
+// to the outbound request pair.LastStunRequestSent recorded.
+func (a *Agent) handleInboundBindingResponse(pair *candidatePair, resp *stun.Message) {
+	pair.updateStatsFromSuccessResponse(pair.LastStunRequestSent())
+}
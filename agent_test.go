@@ -0,0 +1,142 @@
+package ice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/stun"
+)
+
+// mockCandidate is a minimal stand-in for the real Candidate implementation
+// (defined outside this chunk), covering only the methods the code under
+// test actually calls: ID, Priority, String, Equal, and writeTo.
+type mockCandidate struct {
+	id     string
+	pri    uint32
+	writes [][]byte
+}
+
+func (m *mockCandidate) ID() string       { return m.id }
+func (m *mockCandidate) Priority() uint32 { return m.pri }
+func (m *mockCandidate) String() string   { return m.id }
+
+func (m *mockCandidate) Equal(other Candidate) bool {
+	o, ok := other.(*mockCandidate)
+	return ok && o.id == m.id
+}
+
+func (m *mockCandidate) writeTo(b []byte, _ Candidate) (int, error) {
+	m.writes = append(m.writes, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func newTestAgent() *Agent {
+	return &Agent{log: logging.NewDefaultLoggerFactory().NewLogger("ice")}
+}
+
+func TestAgentGetCandidatePairsStats(t *testing.T) {
+	local := &mockCandidate{id: "local1", pri: 10}
+	remote := &mockCandidate{id: "remote1", pri: 20}
+
+	pair := &candidatePair{local: local, remote: remote, state: CandidatePairStateSucceeded, nominated: true}
+	pair.bytesSent.Store(100)
+	pair.requestsSent.Store(3)
+
+	a := &Agent{checklist: []*candidatePair{pair}}
+
+	stats := a.GetCandidatePairsStats()
+	if len(stats) != 1 {
+		t.Fatalf("GetCandidatePairsStats() returned %d entries, want 1", len(stats))
+	}
+
+	got := stats[0]
+	if got.LocalCandidateID != "local1" || got.RemoteCandidateID != "remote1" {
+		t.Fatalf("unexpected candidate IDs: %+v", got)
+	}
+	if got.BytesSent != 100 || got.RequestsSent != 3 {
+		t.Fatalf("unexpected counters: %+v", got)
+	}
+	if !got.Nominated || got.State != CandidatePairStateSucceeded {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+}
+
+func TestAgentHandleInboundBindingRequest(t *testing.T) {
+	local := &mockCandidate{id: "local1"}
+	remote := &mockCandidate{id: "remote1"}
+	pair := &candidatePair{local: local, remote: remote}
+
+	a := newTestAgent()
+	a.localPwd = "pwd"
+
+	req, err := stun.Build(stun.TransactionID, stun.BindingRequest)
+	if err != nil {
+		t.Fatalf("failed to build request: %s", err)
+	}
+
+	a.handleInboundBindingRequest(pair, req)
+
+	if got, want := pair.requestsReceived.Load(), uint64(1); got != want {
+		t.Errorf("requestsReceived = %d, want %d", got, want)
+	}
+	if got, want := pair.responsesSent.Load(), uint64(1); got != want {
+		t.Errorf("responsesSent = %d, want %d", got, want)
+	}
+	if len(local.writes) != 1 {
+		t.Fatalf("handleInboundBindingRequest wrote %d responses, want 1", len(local.writes))
+	}
+}
+
+func TestAgentHandleInboundBindingResponse(t *testing.T) {
+	local := &mockCandidate{id: "local1"}
+	remote := &mockCandidate{id: "remote1"}
+	pair := &candidatePair{local: local, remote: remote}
+	pair.SetLastStunRequestSent(time.Now().Add(-10 * time.Millisecond))
+
+	a := newTestAgent()
+
+	resp, err := stun.Build(stun.TransactionID, stun.BindingSuccess)
+	if err != nil {
+		t.Fatalf("failed to build response: %s", err)
+	}
+
+	a.handleInboundBindingResponse(pair, resp)
+
+	if got, want := pair.responsesReceived.Load(), uint64(1); got != want {
+		t.Fatalf("responsesReceived = %d, want %d", got, want)
+	}
+	if pair.currentRoundTripTimeNanos.Load() <= 0 {
+		t.Fatalf("currentRoundTripTimeNanos was never set from the response")
+	}
+}
+
+func TestAgentSendSTUNCounters(t *testing.T) {
+	local := &mockCandidate{id: "local1"}
+	remote := &mockCandidate{id: "remote1"}
+	pair := &candidatePair{local: local, remote: remote}
+
+	a := newTestAgent()
+	a.checklist = []*candidatePair{pair}
+
+	msg, err := stun.Build(stun.TransactionID, stun.BindingRequest)
+	if err != nil {
+		t.Fatalf("failed to build request: %s", err)
+	}
+
+	a.sendSTUN(msg, local, remote)
+	if got, want := pair.requestsSent.Load(), uint64(1); got != want {
+		t.Errorf("requestsSent after first send = %d, want %d", got, want)
+	}
+	if got, want := a.totalRetransmissions.Load(), uint64(0); got != want {
+		t.Errorf("totalRetransmissions after first send = %d, want %d", got, want)
+	}
+
+	a.sendSTUN(msg, local, remote)
+	if got, want := pair.requestsSent.Load(), uint64(2); got != want {
+		t.Errorf("requestsSent after second send = %d, want %d", got, want)
+	}
+	if got, want := a.totalRetransmissions.Load(), uint64(1); got != want {
+		t.Errorf("totalRetransmissions after second (retransmitted) send = %d, want %d", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package ice
 
 import (
+	"context"
 	"fmt"
 	"sync/atomic"
 	"time"
@@ -8,13 +9,22 @@ import (
 	"github.com/pion/stun"
 )
 
-func newCandidatePair(local, remote Candidate, controlling bool) *candidatePair {
-	return &candidatePair{
+// newCandidatePair builds a pair and starts its per-pair background work.
+// ctx is the owning Agent's lifetime context: the bitrate ticker started here
+// stops when ctx is done even if the pair is never explicitly pruned via
+// Close, so a pair that outlives the checklist can't outlive the agent.
+func newCandidatePair(ctx context.Context, local, remote Candidate, controlling bool) *candidatePair {
+	p := &candidatePair{
 		iceRoleControlling: controlling,
 		remote:             remote,
 		local:              local,
 		state:              CandidatePairStateWaiting,
+		done:               make(chan struct{}),
 	}
+
+	go p.startBitrateTicker(ctx, p.done)
+
+	return p
 }
 
 // candidatePair represents a combination of a local and remote candidate
@@ -22,17 +32,177 @@ type candidatePair struct {
 	iceRoleControlling  bool
 	remote              Candidate
 	local               Candidate
-	bindingRequestCount uint16
+	bindingRequestCount atomic.Uint16
 	state               CandidatePairState
 	nominated           bool
 
-	// The following fields are for generating candidate pair statistics, and need to be accessed through atomics.
-	// The durations are stored as int64 to be used with atomic.AddInt64 and atomic.LoadInt64
-	requestsSent              uint64
-	responsesReceived         uint64
-	currentRoundTripTimeNanos int64
-	totalRoundTripTimeNanos   int64
-	lastStunRequestSent       atomic.Value
+	// The following fields are for generating candidate pair statistics.
+	// They use the typed atomics introduced in Go 1.19 rather than raw
+	// int64/uint64 fields so the compiler enforces the 8-byte alignment
+	// atomic.AddInt64/LoadInt64 require but do not guarantee on 32-bit
+	// platforms (ARMv7, 386, MIPS).
+	requestsSent              atomic.Uint64
+	responsesReceived         atomic.Uint64
+	requestsReceived          atomic.Uint64
+	responsesSent             atomic.Uint64
+	consentRequestsSent       atomic.Uint64
+	currentRoundTripTimeNanos atomic.Int64
+	totalRoundTripTimeNanos   atomic.Int64
+	bytesSent                 atomic.Uint64
+	bytesReceived             atomic.Uint64
+	packetsSent               atomic.Uint64
+	packetsReceived           atomic.Uint64
+
+	lastStunRequestSent         atomic.Value
+	lastPacketSentTimestamp     atomic.Value
+	lastPacketReceivedTimestamp atomic.Value
+
+	// smoothedRoundTripTimeNanos and roundTripTimeVarianceNanos are the RFC 6298
+	// style SRTT/RTTVAR estimators (alpha=1/8, beta=1/4), refined on every
+	// successful Binding response. requestsTimedOut counts requests whose
+	// transaction never resolved within rto(), our loss signal.
+	smoothedRoundTripTimeNanos atomic.Int64
+	roundTripTimeVarianceNanos atomic.Int64
+	requestsTimedOut           atomic.Uint64
+
+	// outgoingBitrate and incomingBitrate are bucketed moving-average
+	// throughput estimators fed by Write and updateStatsFromReadPacket.
+	outgoingBitrate bitrateEstimator
+	incomingBitrate bitrateEstimator
+
+	// consentExpiryValue holds the time.Time (RFC 7675) at which this pair's
+	// ICE consent lapses absent a fresh successful Binding transaction.
+	// consentTimeoutNanos is the configured consent lifetime; it is only set
+	// once consentLoop starts driving this pair, so a zero value means
+	// consent freshness is not being tracked for it.
+	consentExpiryValue  atomic.Value
+	consentTimeoutNanos atomic.Int64
+
+	// done stops startBitrateTicker (and any other per-pair background work)
+	// once the pair is discarded.
+	done chan struct{}
+}
+
+// Close stops this pair's background work (currently just its bitrate
+// ticker). The agent calls this when a pair is dropped from the checklist,
+// e.g. because a better pair was nominated in its place.
+func (p *candidatePair) Close() error {
+	close(p.done)
+	return nil
+}
+
+// clockGranularity is the assumed STUN transaction clock granularity (the "G"
+// term in the RFC 6298 retransmission timeout calculation).
+const clockGranularity = time.Millisecond
+
+// rto returns the current retransmission timeout estimate for this pair,
+// SRTT + max(G, 4*RTTVAR), beyond which an outstanding Binding request is
+// considered lost for the purposes of loss-rate tracking.
+func (p *candidatePair) rto() time.Duration {
+	srtt := time.Duration(p.smoothedRoundTripTimeNanos.Load())
+	rttvar := time.Duration(p.roundTripTimeVarianceNanos.Load())
+	backoff := 4 * rttvar
+	if backoff < clockGranularity {
+		backoff = clockGranularity
+	}
+	return srtt + backoff
+}
+
+// lossRate returns the fraction of sent Binding requests that have timed out,
+// as recorded by recordRequestTimeout.
+func (p *candidatePair) lossRate() float64 {
+	sent := p.requestsSent.Load()
+	if sent == 0 {
+		return 0
+	}
+	return float64(p.requestsTimedOut.Load()) / float64(sent)
+}
+
+// recordRequestTimeout is called by the STUN transaction manager when an
+// outstanding Binding request on this pair ages past rto() without a
+// response, and feeds the pair's loss estimate.
+func (p *candidatePair) recordRequestTimeout() {
+	p.requestsTimedOut.Add(1)
+}
+
+// minRequestTimeout floors the wait trackRequestTimeout arms a check after:
+// rto() falls back to clockGranularity before this pair has any RTT sample,
+// and firing a timeout that fast would flag the latency of an ordinary first
+// request as loss.
+const minRequestTimeout = 500 * time.Millisecond
+
+// trackRequestTimeout arms a one-shot check that calls recordRequestTimeout
+// if this pair's response count hasn't advanced by the time its current
+// rto() (or minRequestTimeout, whichever is longer) elapses, i.e. the
+// just-sent Binding request went unanswered.
+func (p *candidatePair) trackRequestTimeout() {
+	timeout := p.rto()
+	if timeout < minRequestTimeout {
+		timeout = minRequestTimeout
+	}
+
+	responsesAtSend := p.responsesReceived.Load()
+	time.AfterFunc(timeout, func() {
+		if p.responsesReceived.Load() == responsesAtSend {
+			p.recordRequestTimeout()
+		}
+	})
+}
+
+// CandidatePairStats is a snapshot of a candidate pair's statistics, matching
+// the fields of the W3C RTCIceCandidatePairStats dictionary:
+// https://www.w3.org/TR/webrtc-stats/#candidatepair-dict*
+type CandidatePairStats struct {
+	LocalCandidateID            string
+	RemoteCandidateID           string
+	State                       CandidatePairState
+	Nominated                   bool
+	BytesSent                   uint64
+	BytesReceived               uint64
+	PacketsSent                 uint64
+	PacketsReceived             uint64
+	LastPacketSentTimestamp     time.Time
+	LastPacketReceivedTimestamp time.Time
+	TotalRoundTripTime          time.Duration
+	CurrentRoundTripTime        time.Duration
+	RequestsSent                uint64
+	ResponsesReceived           uint64
+	RequestsReceived            uint64
+	ResponsesSent               uint64
+	ConsentRequestsSent         uint64
+	AvailableOutgoingBitrate    float64
+}
+
+// stats builds a CandidatePairStats snapshot for this pair.
+func (p *candidatePair) stats() CandidatePairStats {
+	var lastPacketSent, lastPacketReceived time.Time
+	if v := p.lastPacketSentTimestamp.Load(); v != nil {
+		lastPacketSent = v.(time.Time)
+	}
+	if v := p.lastPacketReceivedTimestamp.Load(); v != nil {
+		lastPacketReceived = v.(time.Time)
+	}
+
+	return CandidatePairStats{
+		LocalCandidateID:            p.local.ID(),
+		RemoteCandidateID:           p.remote.ID(),
+		State:                       p.state,
+		Nominated:                   p.nominated,
+		BytesSent:                   p.bytesSent.Load(),
+		BytesReceived:               p.bytesReceived.Load(),
+		PacketsSent:                 p.packetsSent.Load(),
+		PacketsReceived:             p.packetsReceived.Load(),
+		LastPacketSentTimestamp:     lastPacketSent,
+		LastPacketReceivedTimestamp: lastPacketReceived,
+		TotalRoundTripTime:          time.Duration(p.totalRoundTripTimeNanos.Load()),
+		CurrentRoundTripTime:        time.Duration(p.currentRoundTripTimeNanos.Load()),
+		RequestsSent:                p.requestsSent.Load(),
+		ResponsesReceived:           p.responsesReceived.Load(),
+		RequestsReceived:            p.requestsReceived.Load(),
+		ResponsesSent:               p.responsesSent.Load(),
+		ConsentRequestsSent:         p.consentRequestsSent.Load(),
+		AvailableOutgoingBitrate:    float64(p.OutgoingBitrate()),
+	}
 }
 
 // lastStunRequestSent returns a time.Time indicating the last time
@@ -51,9 +221,76 @@ func (c *candidatePair) SetLastStunRequestSent(t time.Time) {
 
 func (p *candidatePair) updateStatsFromSuccessResponse(requestTimestamp time.Time) {
 	roundTripTime := time.Since(requestTimestamp)
-	atomic.StoreInt64(&p.currentRoundTripTimeNanos, roundTripTime.Nanoseconds())
-	atomic.AddInt64(&p.totalRoundTripTimeNanos, roundTripTime.Nanoseconds())
-	atomic.AddUint64(&p.responsesReceived, 1)
+	p.currentRoundTripTimeNanos.Store(roundTripTime.Nanoseconds())
+	p.totalRoundTripTimeNanos.Add(roundTripTime.Nanoseconds())
+	p.responsesReceived.Add(1)
+	p.updateSmoothedRoundTripTime(roundTripTime)
+
+	// Per RFC 7675 §4, any successful Binding transaction is evidence of
+	// liveness and refreshes consent, not just dedicated consent requests.
+	if timeout := p.consentTimeoutNanos.Load(); timeout > 0 {
+		p.setConsentExpiry(time.Now().Add(time.Duration(timeout)))
+	}
+}
+
+// consentExpiry returns the time.Time at which this pair's RFC 7675 consent
+// lapses, or the zero time if consent freshness is not being tracked for it.
+func (p *candidatePair) consentExpiry() time.Time {
+	v := p.consentExpiryValue.Load()
+	if v == nil {
+		return time.Time{}
+	}
+	return v.(time.Time)
+}
+
+func (p *candidatePair) setConsentExpiry(t time.Time) {
+	p.consentExpiryValue.Store(t)
+}
+
+// updateSmoothedRoundTripTime folds a fresh RTT sample into the pair's RFC
+// 6298 style SRTT/RTTVAR estimators:
+//
+//	first sample:  SRTT = R,                     RTTVAR = R/2
+//	later samples: RTTVAR = (1-beta)*RTTVAR + beta*|SRTT-R|, beta=1/4
+//	               SRTT   = (1-alpha)*SRTT + alpha*R,         alpha=1/8
+func (p *candidatePair) updateSmoothedRoundTripTime(sample time.Duration) {
+	r := sample.Nanoseconds()
+
+	for {
+		oldSRTT := p.smoothedRoundTripTimeNanos.Load()
+		if oldSRTT == 0 {
+			if p.smoothedRoundTripTimeNanos.CompareAndSwap(0, r) {
+				p.roundTripTimeVarianceNanos.Store(r / 2)
+			}
+			return
+		}
+
+		diff := oldSRTT - r
+		if diff < 0 {
+			diff = -diff
+		}
+		oldRTTVAR := p.roundTripTimeVarianceNanos.Load()
+		newRTTVAR := oldRTTVAR - oldRTTVAR/4 + diff/4
+		newSRTT := oldSRTT - oldSRTT/8 + r/8
+
+		if p.smoothedRoundTripTimeNanos.CompareAndSwap(oldSRTT, newSRTT) {
+			p.roundTripTimeVarianceNanos.Store(newRTTVAR)
+			return
+		}
+		// Lost the race with a concurrent response; retry with fresh values.
+	}
+}
+
+// updateStatsFromBindingRequest is called by the connectivity check handler
+// when an inbound Binding request is received on this pair.
+func (p *candidatePair) updateStatsFromBindingRequest() {
+	p.requestsReceived.Add(1)
+}
+
+// updateStatsFromBindingResponse is called by the connectivity check handler
+// after a Binding success response has been written back to the peer.
+func (p *candidatePair) updateStatsFromBindingResponse() {
+	p.responsesSent.Add(1)
 }
 
 func (p *candidatePair) String() string {
@@ -114,12 +351,65 @@ func (p *candidatePair) Priority() uint64 {
 }
 
 func (p *candidatePair) Write(b []byte) (int, error) {
-	return p.local.writeTo(b, p.remote)
+	n, err := p.local.writeTo(b, p.remote)
+	if err == nil {
+		p.bytesSent.Add(uint64(n))
+		p.packetsSent.Add(1)
+		p.lastPacketSentTimestamp.Store(time.Now())
+		p.outgoingBitrate.add(uint64(n))
+	}
+	return n, err
 }
 
-func (a *Agent) sendSTUN(msg *stun.Message, local, remote Candidate) {
+// updateStatsFromReadPacket records a packet read from the wire on this pair's
+// local candidate, so GetCandidatePairsStats reports incoming byte/packet
+// counts alongside the outgoing counters tracked by Write.
+func (p *candidatePair) updateStatsFromReadPacket(n int) {
+	p.bytesReceived.Add(uint64(n))
+	p.packetsReceived.Add(1)
+	p.lastPacketReceivedTimestamp.Store(time.Now())
+	p.incomingBitrate.add(uint64(n))
+}
+
+// writeSTUN writes msg to remote via local without the outbound-request
+// bookkeeping sendSTUN performs. Use this for STUN messages that aren't new
+// requests, e.g. Binding success responses, which shouldn't be counted
+// against RequestsSent.
+func (a *Agent) writeSTUN(msg *stun.Message, local, remote Candidate) error {
 	_, err := local.writeTo(msg.Raw, remote)
 	if err != nil {
 		a.log.Tracef("failed to send STUN message: %s", err)
 	}
+	return err
+}
+
+func (a *Agent) sendSTUN(msg *stun.Message, local, remote Candidate) {
+	if err := a.writeSTUN(msg, local, remote); err != nil {
+		return
+	}
+
+	pair := a.findPair(local, remote)
+	if pair == nil {
+		return
+	}
+
+	pair.requestsSent.Add(1)
+	if pair.bindingRequestCount.Add(1) > 1 {
+		a.totalRetransmissions.Add(1)
+	}
+	pair.SetLastStunRequestSent(time.Now())
+	pair.trackRequestTimeout()
+}
+
+// GetCandidatePairsStats returns a snapshot of RTCIceCandidatePairStats-style
+// statistics for every candidate pair on the agent's checklist.
+func (a *Agent) GetCandidatePairsStats() []CandidatePairStats {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	stats := make([]CandidatePairStats, 0, len(a.checklist))
+	for _, p := range a.checklist {
+		stats = append(stats, p.stats())
+	}
+	return stats
 }
@@ -0,0 +1,84 @@
+package ice
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// bitrateBucketCount and bitrateBucketDuration define the moving-average
+// window used by candidatePair's throughput estimator: bitrateBucketCount
+// buckets of bitrateBucketDuration each, so a pair's bitrate reflects the
+// last bitrateBucketCount*bitrateBucketDuration of traffic.
+const (
+	bitrateBucketCount    = 10
+	bitrateBucketDuration = 100 * time.Millisecond
+)
+
+// bitrateEstimator is a lock-free, bucketed moving-average byte counter. Its
+// counters use the typed atomics from Go 1.19 so alignment on 32-bit
+// platforms is enforced by the compiler, the same reasoning that applies to
+// candidatePair's stats fields in candidatepair.go. add atomically adds to
+// the bucket for "now", and a background ticker rotates through the ring,
+// zeroing the bucket that is about to be reused so its bytes age out of the
+// running sum.
+type bitrateEstimator struct {
+	buckets [bitrateBucketCount]atomic.Uint64
+	sum     atomic.Uint64
+	index   atomic.Uint32
+}
+
+// add records n bytes in the bucket for the current tick.
+func (b *bitrateEstimator) add(n uint64) {
+	b.buckets[b.index.Load()%bitrateBucketCount].Add(n)
+	b.sum.Add(n)
+}
+
+// advance rotates the ring forward by one bucket, evicting the bytes in the
+// bucket that is about to be overwritten from the running sum.
+func (b *bitrateEstimator) advance() {
+	next := b.index.Add(1) % bitrateBucketCount
+	evicted := b.buckets[next].Swap(0)
+	b.sum.Add(^(evicted - 1)) // atomic subtract
+}
+
+// bitsPerSecond returns a smoothed bits-per-second reading over the full
+// bucket window.
+func (b *bitrateEstimator) bitsPerSecond() uint64 {
+	window := bitrateBucketDuration * bitrateBucketCount
+	return b.sum.Load() * 8 * uint64(time.Second) / uint64(window)
+}
+
+// OutgoingBitrate returns a smoothed estimate, in bits per second, of the
+// traffic this pair has written over the last bitrateBucketCount*
+// bitrateBucketDuration window.
+func (p *candidatePair) OutgoingBitrate() uint64 {
+	return p.outgoingBitrate.bitsPerSecond()
+}
+
+// IncomingBitrate returns a smoothed estimate, in bits per second, of the
+// traffic this pair has read over the same window as OutgoingBitrate.
+func (p *candidatePair) IncomingBitrate() uint64 {
+	return p.incomingBitrate.bitsPerSecond()
+}
+
+// startBitrateTicker rotates both of the pair's bitrate buckets every
+// bitrateBucketDuration until stop is closed or ctx is done, whichever comes
+// first. The agent starts one of these per pair alongside its other per-pair
+// background work.
+func (p *candidatePair) startBitrateTicker(ctx context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(bitrateBucketDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.outgoingBitrate.advance()
+			p.incomingBitrate.advance()
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ice
+
+import "testing"
+
+func TestBitrateEstimatorBitsPerSecond(t *testing.T) {
+	var b bitrateEstimator
+
+	b.add(125) // 125 bytes over the 1s window == 1000 bits/sec
+
+	if got, want := b.bitsPerSecond(), uint64(1000); got != want {
+		t.Fatalf("bitsPerSecond() = %d, want %d", got, want)
+	}
+}
+
+func TestBitrateEstimatorAdvanceEvictsOldBuckets(t *testing.T) {
+	var b bitrateEstimator
+
+	b.add(250)
+
+	// A full rotation of the ring should age every byte out of the window,
+	// even with no further adds in between.
+	for i := 0; i < bitrateBucketCount; i++ {
+		b.advance()
+	}
+
+	if got := b.sum.Load(); got != 0 {
+		t.Fatalf("sum after a full rotation = %d, want 0", got)
+	}
+}
+
+func TestBitrateEstimatorAdvanceKeepsRecentBuckets(t *testing.T) {
+	var b bitrateEstimator
+
+	b.add(100)
+	b.advance()
+	b.add(50)
+
+	if got, want := b.sum.Load(), uint64(150); got != want {
+		t.Fatalf("sum after partial rotation = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package ice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandidatePairInboundCounters(t *testing.T) {
+	p := &candidatePair{}
+
+	p.updateStatsFromBindingRequest()
+	p.updateStatsFromBindingRequest()
+	p.updateStatsFromBindingResponse()
+
+	if got, want := p.requestsReceived.Load(), uint64(2); got != want {
+		t.Errorf("requestsReceived = %d, want %d", got, want)
+	}
+	if got, want := p.responsesSent.Load(), uint64(1); got != want {
+		t.Errorf("responsesSent = %d, want %d", got, want)
+	}
+
+	p.updateStatsFromReadPacket(128)
+	p.updateStatsFromReadPacket(64)
+
+	if got, want := p.bytesReceived.Load(), uint64(192); got != want {
+		t.Errorf("bytesReceived = %d, want %d", got, want)
+	}
+	if got, want := p.packetsReceived.Load(), uint64(2); got != want {
+		t.Errorf("packetsReceived = %d, want %d", got, want)
+	}
+	if p.lastPacketReceivedTimestamp.Load() == nil {
+		t.Error("lastPacketReceivedTimestamp was never set")
+	}
+}
+
+func TestCandidatePairLossRate(t *testing.T) {
+	p := &candidatePair{}
+
+	if got := p.lossRate(); got != 0 {
+		t.Fatalf("lossRate() with no requests sent = %v, want 0", got)
+	}
+
+	p.requestsSent.Store(4)
+	p.recordRequestTimeout()
+
+	if got, want := p.lossRate(), 0.25; got != want {
+		t.Fatalf("lossRate() = %v, want %v", got, want)
+	}
+}
+
+func TestCandidatePairSmoothedRTT(t *testing.T) {
+	p := &candidatePair{}
+
+	p.updateSmoothedRoundTripTime(100 * time.Millisecond)
+	if got, want := p.smoothedRoundTripTimeNanos.Load(), (100 * time.Millisecond).Nanoseconds(); got != want {
+		t.Fatalf("SRTT after first sample = %d, want %d", got, want)
+	}
+	if got, want := p.roundTripTimeVarianceNanos.Load(), (50 * time.Millisecond).Nanoseconds(); got != want {
+		t.Fatalf("RTTVAR after first sample = %d, want %d", got, want)
+	}
+
+	// Second sample should pull SRTT towards the new value, not jump to it.
+	p.updateSmoothedRoundTripTime(200 * time.Millisecond)
+	srtt := time.Duration(p.smoothedRoundTripTimeNanos.Load())
+	if srtt <= 100*time.Millisecond || srtt >= 200*time.Millisecond {
+		t.Fatalf("SRTT after second sample = %s, want strictly between 100ms and 200ms", srtt)
+	}
+}
+
+func TestCandidatePairRTO(t *testing.T) {
+	p := &candidatePair{}
+
+	// With no samples yet, RTO should fall back to the clock granularity.
+	if got := p.rto(); got != clockGranularity {
+		t.Fatalf("rto() with no samples = %s, want %s", got, clockGranularity)
+	}
+}
@@ -0,0 +1,34 @@
+package ice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsentIntervalDefaultBounds(t *testing.T) {
+	a := &Agent{}
+
+	min := 5 * time.Second / 2
+	max := 5 * time.Second * 3 / 2
+
+	for i := 0; i < 100; i++ {
+		got := a.consentInterval()
+		if got < min || got > max {
+			t.Fatalf("consentInterval() = %s, want within [%s, %s]", got, min, max)
+		}
+	}
+}
+
+func TestConsentIntervalRespectsConfiguredValue(t *testing.T) {
+	a := &Agent{consentIntervalSec: 10}
+
+	min := 10 * time.Second / 2
+	max := 10 * time.Second * 3 / 2
+
+	for i := 0; i < 100; i++ {
+		got := a.consentInterval()
+		if got < min || got > max {
+			t.Fatalf("consentInterval() = %s, want within [%s, %s]", got, min, max)
+		}
+	}
+}
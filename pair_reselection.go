@@ -0,0 +1,115 @@
+package ice
+
+import (
+	"context"
+	"time"
+)
+
+// PairQuality summarizes a candidate pair's recently observed smoothed RTT
+// and loss rate, the inputs a PairReselectionPolicy compares to decide
+// whether a pair should replace the currently nominated one.
+type PairQuality struct {
+	LocalCandidateID  string
+	RemoteCandidateID string
+	SmoothedRTT       time.Duration
+	RTTVariance       time.Duration
+	LossRate          float64
+}
+
+// PairReselectionPolicy reports whether candidate should replace nominated as
+// the agent's selected pair. It is consulted once per keepaliveInterval for
+// every succeeded pair; the controlling agent only re-nominates once a
+// policy keeps picking the same candidate for reselectionConfirmations
+// consecutive checks, so a policy only needs to compare the two pairs it is
+// given rather than track history itself.
+//
+// Set AgentConfig.PairReselectionPolicy to nil to disable automatic
+// re-nomination entirely.
+type PairReselectionPolicy func(nominated, candidate PairQuality) bool
+
+// reselectionConfirmations is the number of consecutive checks a candidate
+// pair must win before the controlling agent re-nominates it.
+const reselectionConfirmations = 3
+
+// defaultPairReselectionPolicy re-nominates a pair whose smoothed RTT is at
+// least 25% lower than the nominated pair's and whose loss rate is also
+// lower.
+func defaultPairReselectionPolicy(nominated, candidate PairQuality) bool {
+	if candidate.SmoothedRTT <= 0 || nominated.SmoothedRTT <= 0 {
+		return false
+	}
+	return float64(candidate.SmoothedRTT) <= float64(nominated.SmoothedRTT)*0.75 &&
+		candidate.LossRate < nominated.LossRate
+}
+
+// quality returns the PairQuality snapshot used by PairReselectionPolicy.
+func (p *candidatePair) quality() PairQuality {
+	return PairQuality{
+		LocalCandidateID:  p.local.ID(),
+		RemoteCandidateID: p.remote.ID(),
+		SmoothedRTT:       time.Duration(p.smoothedRoundTripTimeNanos.Load()),
+		RTTVariance:       time.Duration(p.roundTripTimeVarianceNanos.Load()),
+		LossRate:          p.lossRate(),
+	}
+}
+
+// pairReselectionLoop periodically compares the nominated pair's quality
+// against every other succeeded pair on the checklist and, per
+// a.pairReselectionPolicy, issues a fresh USE-CANDIDATE Binding request to
+// re-nominate a pair that is clearly and consistently better. It only runs
+// for the controlling agent, since only the controlling agent may nominate
+// pairs, and exits as soon as ctx is done.
+func (a *Agent) pairReselectionLoop(ctx context.Context) {
+	policy := a.pairReselectionPolicy
+	if policy == nil || !a.isControlling {
+		return
+	}
+
+	ticker := time.NewTicker(a.keepaliveInterval)
+	defer ticker.Stop()
+
+	wins := make(map[*candidatePair]int)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.checkPairReselection(policy, wins)
+		}
+	}
+}
+
+func (a *Agent) checkPairReselection(policy PairReselectionPolicy, wins map[*candidatePair]int) {
+	a.lock.Lock()
+	nominatedPair := a.getSelectedPair()
+	candidates := append([]*candidatePair(nil), a.checklist...)
+	a.lock.Unlock()
+
+	if nominatedPair == nil {
+		return
+	}
+	nominatedQuality := nominatedPair.quality()
+
+	for _, pair := range candidates {
+		if pair == nominatedPair || pair.state != CandidatePairStateSucceeded {
+			continue
+		}
+
+		if policy(nominatedQuality, pair.quality()) {
+			wins[pair]++
+		} else {
+			wins[pair] = 0
+		}
+
+		if wins[pair] >= reselectionConfirmations {
+			a.log.Infof("re-nominating pair %s (was %s) after %d consecutive quality checks",
+				pair, nominatedPair, wins[pair])
+			a.reNominatePair(pair)
+			for p := range wins {
+				delete(wins, p)
+			}
+			return
+		}
+	}
+}